@@ -2,20 +2,24 @@ package config
 
 import (
     "fmt"
-    "bufio"
-    "io"
-    "os"
-    "strings"
 )
 
+/* ConfLineError描述单独一行配置解析失败的原因
+ * Line: 行号(从1开始), 在无法确定行号的场景(如非逐行格式的json/yaml/toml顶层校验)留0
+ * Reason: 出错原因, 例如"key cannot be empty"
+ * Text: 出错的那一行原文
+ */
 type ConfLineError struct {
-    line string
-    error string
+    Line int
+    Reason string
+    Text string
 }
 
+/* ConfFileError在ConfLineError之上附加文件名, 是_parse_file/_include真正往外抛出的错误类型
+ */
 type ConfFileError struct {
-    fname string
-    error *ConfLineError
+    File string
+    Err *ConfLineError
 }
 
 type NoSuchSectionError struct {
@@ -27,12 +31,30 @@ type NoSuchKeyError struct {
     key string
 }
 
+/* ErrMaxDepth在include嵌套或%(name)s插值展开超过各自的最大递归深度时产生
+ * 插值超限时Parse_conf直接返回*ErrMaxDepth; include超限时它只以错误文本的形式出现在
+ * Parse_conf返回的*ConfFileError里, errors.As取不到*ErrMaxDepth
+ */
+type ErrMaxDepth struct {
+    limit int
+}
+
+/* ErrIncludeCycle在include指令形成环(一个文件直接或间接include了自己)时由_include产生
+ * 它只以错误文本的形式出现在Parse_conf返回的*ConfFileError里, errors.As取不到*ErrIncludeCycle
+ */
+type ErrIncludeCycle struct {
+    fname string
+}
+
 func (cle *ConfLineError) Error() string {
-    return fmt.Sprintf("%s: %s", cle.line, cle.error)
+    if cle.Line > 0 {
+        return fmt.Sprintf("line %d: %s: %q", cle.Line, cle.Reason, cle.Text)
+    }
+    return fmt.Sprintf("%s: %q", cle.Reason, cle.Text)
 }
 
 func (cfe *ConfFileError) Error() string {
-    return fmt.Sprintf("configuration file error: %s\n\t%s", cfe.fname, cfe.error)
+    return fmt.Sprintf("configuration file %q: %s", cfe.File, cfe.Err)
 }
 
 func (nsse *NoSuchSectionError) Error() string {
@@ -43,182 +65,102 @@ func (nske *NoSuchKeyError) Error() string {
     return fmt.Sprintf("no such key [%s] was set under section [%s]", nske.key, nske.sec_name)
 }
 
-func console_log(level, message string) {
-    fmt.Printf("[%s] %s\n", level, message)
+func (emd *ErrMaxDepth) Error() string {
+    return fmt.Sprintf("exceeded max recursion depth (%d)", emd.limit)
 }
 
-/* ini配置格式的节, 形如[default]
- * name: 节名
- * fields: 节下的配置项及其值
- */
-type Section struct {
-    name string
-    fields map[string]string
+func (eic *ErrIncludeCycle) Error() string {
+    return fmt.Sprintf("include cycle detected at %q", eic.fname)
 }
 
-type PState struct {
-    in_sec bool
-    cur_sec *Section
+func console_log(level, message string) {
+    fmt.Printf("[%s] %s\n", level, message)
 }
 
-/* 配置句柄
- * conf_files: 存放配置文件路径及对应的已打开文件句柄
- * sections: 存放已解析的节
+/* Configer是各配置格式适配器(ini/json/yaml/toml/env)共同实现的接口
+ * key统一采用"section::subkey"的形式寻址; 不分节的格式(如env)直接用"subkey"寻址, 空section的"::subkey"写法在各适配器中都找不到值
  */
-type Config struct {
-    conf_files map[string]*os.File
-    sections map[string]*Section
-    _pstate *PState
-}
-
-/* Section构造函数
+type Configer interface {
+    Get(key string) (string, error)
+    String(key string) string
+    Int(key string) (int, error)
+    Int64(key string) (int64, error)
+    Bool(key string) (bool, error)
+    Float(key string) (float64, error)
+    DIY(key string) (interface{}, error)
+    Set(key, value string) error
+    Sections() []string
+    Keys(section string) []string
+    SaveTo(filename string) error
+}
+
+/* fileLoader由可以从一个或多个文件填充自己的Configer实现, NewConfig借助它完成加载
  */
-func NewSection(name string) *Section {
-    var _section Section
-    _section.name = name
-    _section.fields = make(map[string]string)
-    return &_section
+type fileLoader interface {
+    loadFiles(filenames []string) error
 }
 
-/* Config构造函数
+/* dataLoader由可以从内存数据填充自己的Configer实现, NewConfigData借助它完成加载
  */
-func NewConfig(files []string) *Config {
-    var _config Config
-    _config.conf_files = make(map[string]*os.File)
-    for _, file := range files {
-        _config.conf_files[file] = nil
-    }
-    _config.sections = make(map[string]*Section)
-    _config._pstate = &PState{false, nil}
-    return &_config
+type dataLoader interface {
+    loadData(data []byte) error
 }
 
-/* 打开配置文件
- */
-func (config *Config) _open_files() (bool, error) {
-    for fpath, fobj := range config.conf_files {
-        if fobj == nil {
-            fobj, err := os.Open(fpath)
-            if err != nil {
-                console_log("ERROR", err.Error())
-                return false, err
-            } else {
-                config.conf_files[fpath] = fobj
-            }
-        }
-    }
-    return true, nil
-}
+var adapters = make(map[string]func() Configer)
 
-/* 解析单个行
+/* Register登记一个配置格式适配器的工厂函数, 供NewConfig/NewConfigData按名字查找
+ * 重复Register同一个adapter名字会panic, 这与标准库database/sql的Register约定一致
  */
-func (config *Config) _parse_line(line string) (bool, *ConfLineError) {
-    if len(line) > 0 {
-        line = strings.TrimSpace(line)
-        line_len := len(line)
-        if line_len > 0 {
-            if (line[0] == '[' && line[line_len - 1] == ']') {
-                sec_name := line[1: line_len - 1]
-                if len(sec_name) == 0 {
-                    return false, &ConfLineError{line, "section name cannot be empty"}
-                }
-                pSection, _in := config.sections[sec_name]
-                if !_in {
-                    pSection = NewSection(sec_name)
-                    config.sections[sec_name] = pSection
-                }
-                config._pstate.in_sec = true
-                config._pstate.cur_sec = pSection
-            } else {
-                first_equation_pos := strings.Index(line, "=")
-                if first_equation_pos <= 0 {
-                    return false, &ConfLineError{line, "invalid configuration line"}
-                }
-                key := line[: first_equation_pos]
-                key = strings.TrimSpace(key)
-                if len(key) == 0 {
-                    return false, &ConfLineError{line, "key cannot be empty"}
-                }
-                value := line[first_equation_pos + 1:]
-                value = strings.TrimSpace(value)
-                if value[0] == '"' && value[len(value) - 1] == '"'{
-                    value = value[1: len(value) - 1]
-                }
-                if !config._pstate.in_sec {
-                    return false, &ConfLineError{line, "configuration line without section"}
-                }
-                cur_sec := config._pstate.cur_sec
-                // we just cover the value set before
-                cur_sec.fields[key] = value
-            }
-        }
+func Register(adapter string, factory func() Configer) {
+    if factory == nil {
+        panic("config: Register factory is nil")
     }
-    
-    return true, nil
-}
-
-/* 解析单个配置文件
- */
-func (config *Config) _parse_file(fname string, file *os.File) (bool, error) {
-    if file != nil {
-        br := bufio.NewReader(file)
-        //in_section := false
-        //var cur_section *Section = nil
-        for {
-            line, _, err := br.ReadLine()
-            if err == nil || err == io.EOF {
-                _success, _err := config._parse_line(string(line))
-                if !_success {
-                    return false, &ConfFileError{fname, _err}
-                }
-            }
-            if err == io.EOF {
-                break
-            } else if err != nil {
-                console_log("ERROR", err.Error())
-                return false, err
-            }
-        }
+    if _, dup := adapters[adapter]; dup {
+        panic("config: Register called twice for adapter " + adapter)
     }
-    return true, nil
+    adapters[adapter] = factory
 }
 
-/* 解析完成后的善后工作
- */
-func (config *Config) finalize() {
-    for _, fobj := range config.conf_files {
-        fobj.Close()
+func new_adapter(adapter string) (Configer, error) {
+    factory, ok := adapters[adapter]
+    if !ok {
+        return nil, fmt.Errorf("config: unknown adapter %q (forgotten import?)", adapter)
     }
+    return factory(), nil
 }
 
-/* 解析配置
+/* NewConfig按adapter指定的格式("ini"/"json"/"yaml"/"toml"/"env")构造一个Configer并从filenames加载数据
+ * 大多数格式只接受一个文件名; ini适配器支持传入多个文件做分层覆盖, 参见Config.Source
  */
-func (config *Config) Parse_conf() (bool, error) {
-    _success, _err := config._open_files()
-    defer config.finalize()
-    if !_success {
-        return false, _err
+func NewConfig(adapter string, filenames ...string) (Configer, error) {
+    configer, err := new_adapter(adapter)
+    if err != nil {
+        return nil, err
     }
-    for fname, fobj := range config.conf_files {
-        _success, _err = config._parse_file(fname, fobj)
-        if !_success {
-            return false, _err
-        }
+    loader, ok := configer.(fileLoader)
+    if !ok {
+        return nil, fmt.Errorf("config: adapter %q cannot be loaded from a file", adapter)
     }
-    return true, nil
+    if err := loader.loadFiles(filenames); err != nil {
+        return nil, err
+    }
+    return configer, nil
 }
 
-/* 获取指定节下的指定关键字的值
+/* NewConfigData与NewConfig类似, 但直接从内存中的data构造Configer, 不涉及文件IO
+ * 只有json/yaml/toml适配器能从内存数据加载; 对ini和env适配器返回错误
  */
-func (config *Config) Get(section, key string) (string, error) {
-    pSection, _in := config.sections[section]
-    if !_in {
-        return "", &NoSuchSectionError{section}
+func NewConfigData(adapter string, data []byte) (Configer, error) {
+    configer, err := new_adapter(adapter)
+    if err != nil {
+        return nil, err
+    }
+    loader, ok := configer.(dataLoader)
+    if !ok {
+        return nil, fmt.Errorf("config: adapter %q cannot be loaded from data", adapter)
     }
-    value, _in := pSection.fields[key]
-    if !_in {
-        return "", &NoSuchKeyError{section, key}
+    if err := loader.loadData(data); err != nil {
+        return nil, err
     }
-    return value, nil
+    return configer, nil
 }
-
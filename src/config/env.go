@@ -0,0 +1,108 @@
+package config
+
+import (
+    "os"
+    "strconv"
+    "strings"
+)
+
+/* envConfig是Configer在进程环境变量上的适配器, 没有节的概念, Sections()恒为空
+ */
+type envConfig struct {
+    values map[string]string
+}
+
+func init() {
+    Register("env", func() Configer {
+        return &envConfig{values: make(map[string]string)}
+    })
+}
+
+/* loadFiles对env适配器而言忽略filenames: 它只是把当前进程的全部环境变量(os.Environ())重新读入values
+ */
+func (c *envConfig) loadFiles(filenames []string) error {
+    c.values = make(map[string]string)
+    for _, kv := range os.Environ() {
+        if pos := strings.Index(kv, "="); pos > 0 {
+            c.values[kv[:pos]] = kv[pos+1:]
+        }
+    }
+    return nil
+}
+
+func (c *envConfig) Get(key string) (string, error) {
+    value, _in := c.values[key]
+    if !_in {
+        return "", &NoSuchKeyError{"", key}
+    }
+    return value, nil
+}
+
+func (c *envConfig) String(key string) string {
+    value, _ := c.Get(key)
+    return value
+}
+
+func (c *envConfig) Int(key string) (int, error) {
+    value, err := c.Get(key)
+    if err != nil {
+        return 0, err
+    }
+    return strconv.Atoi(value)
+}
+
+func (c *envConfig) Int64(key string) (int64, error) {
+    value, err := c.Get(key)
+    if err != nil {
+        return 0, err
+    }
+    return strconv.ParseInt(value, 10, 64)
+}
+
+func (c *envConfig) Bool(key string) (bool, error) {
+    value, err := c.Get(key)
+    if err != nil {
+        return false, err
+    }
+    return strconv.ParseBool(value)
+}
+
+func (c *envConfig) Float(key string) (float64, error) {
+    value, err := c.Get(key)
+    if err != nil {
+        return 0, err
+    }
+    return strconv.ParseFloat(value, 64)
+}
+
+func (c *envConfig) DIY(key string) (interface{}, error) {
+    return c.Get(key)
+}
+
+func (c *envConfig) Set(key, value string) error {
+    c.values[key] = value
+    return os.Setenv(key, value)
+}
+
+func (c *envConfig) Sections() []string {
+    return nil
+}
+
+/* Keys只在section为""(env没有节的概念, 对应"无节")时返回全部变量名, 其他section一律返回nil
+ */
+func (c *envConfig) Keys(section string) []string {
+    if section != "" {
+        return nil
+    }
+    keys := make([]string, 0, len(c.values))
+    for key := range c.values {
+        keys = append(keys, key)
+    }
+    return keys
+}
+
+/* SaveTo对env适配器没有意义(无法把环境变量"写回"一个文件), 返回错误
+ */
+func (c *envConfig) SaveTo(filename string) error {
+    return &NoSuchSectionError{"env adapter does not support SaveTo"}
+}
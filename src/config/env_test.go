@@ -0,0 +1,32 @@
+package config
+
+import (
+    "testing"
+)
+
+/* TestEnvConfigKeysRespectsSection覆盖chunk0-2: env没有节的概念, 所以Keys只应该在
+ * section==""时返回变量名, 其他section必须返回nil, 而不是忽略参数总是返回全部变量
+ */
+func TestEnvConfigKeysRespectsSection(t *testing.T) {
+    t.Setenv("GOWEB_ENV_TEST_KEY", "1")
+
+    configer, err := NewConfig("env")
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    keys := configer.Keys("")
+    found := false
+    for _, key := range keys {
+        if key == "GOWEB_ENV_TEST_KEY" {
+            found = true
+        }
+    }
+    if !found {
+        t.Fatalf("Keys(\"\") = %v, want it to include GOWEB_ENV_TEST_KEY", keys)
+    }
+
+    if got := configer.Keys("anything"); got != nil {
+        t.Fatalf("Keys(\"anything\") = %v, want nil", got)
+    }
+}
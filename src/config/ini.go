@@ -0,0 +1,456 @@
+package config
+
+import (
+    "bufio"
+    "io"
+    "os"
+    "path/filepath"
+    "strings"
+    "sync"
+)
+
+/* ini配置格式的节, 形如[default]
+ * name: 节名
+ * fields: 节下的配置项及其值
+ * keys: fields的插入顺序, map本身不保证顺序, SaveTo依赖这份顺序来还原文件
+ * comment: 紧跟在本节[section]行之前的注释块(已去掉#/;前缀), 多行以\n连接
+ * key_comments: 紧跟在某个key行之前的注释块, 以key为索引
+ */
+type Section struct {
+    name string
+    fields map[string]string
+    keys []string
+    comment string
+    key_comments map[string]string
+}
+
+type PState struct {
+    in_sec bool
+    cur_sec *Section
+    pending_comment []string
+    cur_file string
+    cur_line int
+    include_depth int
+}
+
+/* 配置句柄, 即ini适配器的底层数据结构
+ * conf_files: 存放配置文件路径及对应的已打开文件句柄
+ * file_order: conf_files的加载顺序, 后面的文件在同一个(section, key)上覆盖前面的文件
+ * sections: 存放已解析的节
+ * section_order: sections的插入顺序, SaveTo依赖这份顺序来还原文件
+ * provenance: 记录每个(section, key)最终取值来自哪个文件, 供Source查询
+ * visited: include指令正在处理中的文件(绝对路径), 用于检测include环
+ * mu: 保护sections在热重载时的并发读写
+ * watcher: Watch/WatchWithOptions启动的后台监控状态, 一次性解析的Config不会设置它
+ */
+type Config struct {
+    conf_files map[string]*os.File
+    file_order []string
+    sections map[string]*Section
+    section_order []string
+    provenance map[string]map[string]string
+    visited map[string]bool
+    _pstate *PState
+    mu sync.RWMutex
+    watcher *watcher
+}
+
+// maxIncludeDepth是include指令允许的最大递归深度, 超出视为失控的嵌套而非合法配置
+const maxIncludeDepth = 200
+
+// maxInterpolationDepth是%(name)s插值替换允许的最大轮次, 超出通常意味着存在循环引用
+const maxInterpolationDepth = 200
+
+/* Section构造函数
+ */
+func NewSection(name string) *Section {
+    var _section Section
+    _section.name = name
+    _section.fields = make(map[string]string)
+    _section.key_comments = make(map[string]string)
+    return &_section
+}
+
+/* newIniBackend是ini适配器内部使用的构造函数, 对外统一通过NewConfig("ini", files...)暴露
+ */
+func newIniBackend(files []string) *Config {
+    var _config Config
+    _config.conf_files = make(map[string]*os.File)
+    _config.file_order = make([]string, 0, len(files))
+    for _, file := range files {
+        _config.conf_files[file] = nil
+        _config.file_order = append(_config.file_order, file)
+    }
+    _config.sections = make(map[string]*Section)
+    _config.provenance = make(map[string]map[string]string)
+    _config.visited = make(map[string]bool)
+    _config._pstate = &PState{false, nil, nil, "", 0, 0}
+    return &_config
+}
+
+/* 打开配置文件
+ */
+func (config *Config) _open_files() (bool, error) {
+    for fpath, fobj := range config.conf_files {
+        if fobj == nil {
+            fobj, err := os.Open(fpath)
+            if err != nil {
+                console_log("ERROR", err.Error())
+                return false, err
+            } else {
+                config.conf_files[fpath] = fobj
+            }
+        }
+    }
+    return true, nil
+}
+
+/* 解析单个行
+ */
+func (config *Config) _parse_line(line string) (bool, *ConfLineError) {
+    if len(line) > 0 {
+        line = strings.TrimSpace(line)
+        line_len := len(line)
+        if line_len > 0 {
+            if line[0] == '#' || line[0] == ';' {
+                comment := strings.TrimSpace(line[1:])
+                config._pstate.pending_comment = append(config._pstate.pending_comment, comment)
+                return true, nil
+            }
+            if (line[0] == '[' && line[line_len - 1] == ']') {
+                sec_name := line[1: line_len - 1]
+                if len(sec_name) == 0 {
+                    return false, &ConfLineError{Line: config._pstate.cur_line, Reason: "section name cannot be empty", Text: line}
+                }
+                pSection, _in := config.sections[sec_name]
+                if !_in {
+                    pSection = NewSection(sec_name)
+                    config.sections[sec_name] = pSection
+                    config.section_order = append(config.section_order, sec_name)
+                    pSection.comment = config._pstate._take_pending_comment()
+                } else {
+                    config._pstate._take_pending_comment()
+                }
+                config._pstate.in_sec = true
+                config._pstate.cur_sec = pSection
+            } else {
+                first_equation_pos := strings.Index(line, "=")
+                if first_equation_pos < 0 {
+                    return false, &ConfLineError{Line: config._pstate.cur_line, Reason: "invalid configuration line", Text: line}
+                }
+                if first_equation_pos == 0 {
+                    return false, &ConfLineError{Line: config._pstate.cur_line, Reason: "key cannot be empty", Text: line}
+                }
+                key := line[: first_equation_pos]
+                key = strings.TrimSpace(key)
+                if len(key) == 0 {
+                    return false, &ConfLineError{Line: config._pstate.cur_line, Reason: "key cannot be empty", Text: line}
+                }
+                value := line[first_equation_pos + 1:]
+                value = strings.TrimSpace(value)
+                if len(value) >= 2 && value[0] == '"' && value[len(value) - 1] == '"'{
+                    value = value[1: len(value) - 1]
+                }
+                if key == "include" {
+                    config._pstate._take_pending_comment()
+                    if _success, _err := config._include(value); !_success {
+                        return false, &ConfLineError{Line: config._pstate.cur_line, Reason: _err.Error(), Text: line}
+                    }
+                    return true, nil
+                }
+                if !config._pstate.in_sec {
+                    return false, &ConfLineError{Line: config._pstate.cur_line, Reason: "configuration line without section", Text: line}
+                }
+                cur_sec := config._pstate.cur_sec
+                if _, _in := cur_sec.fields[key]; !_in {
+                    cur_sec.keys = append(cur_sec.keys, key)
+                }
+                // we just cover the value set before
+                cur_sec.fields[key] = value
+                if comment := config._pstate._take_pending_comment(); comment != "" {
+                    cur_sec.key_comments[key] = comment
+                }
+                config._record_source(cur_sec.name, key, config._pstate.cur_file)
+            }
+        }
+    }
+
+    return true, nil
+}
+
+/* _take_pending_comment取出之前累积的注释行(合并为一个多行字符串)并清空累积区
+ */
+func (ps *PState) _take_pending_comment() string {
+    if len(ps.pending_comment) == 0 {
+        return ""
+    }
+    comment := strings.Join(ps.pending_comment, "\n")
+    ps.pending_comment = nil
+    return comment
+}
+
+/* 解析单个配置文件
+ */
+func (config *Config) _parse_file(fname string, file *os.File) (bool, error) {
+    if file != nil {
+        prev_file := config._pstate.cur_file
+        prev_line := config._pstate.cur_line
+        config._pstate.cur_file = fname
+        config._pstate.cur_line = 0
+        defer func() {
+            config._pstate.cur_file = prev_file
+            config._pstate.cur_line = prev_line
+        }()
+
+        br := bufio.NewReader(file)
+        //in_section := false
+        //var cur_section *Section = nil
+        for {
+            line, _, err := br.ReadLine()
+            if err == nil || err == io.EOF {
+                config._pstate.cur_line++
+                _success, _err := config._parse_line(string(line))
+                if !_success {
+                    return false, &ConfFileError{File: fname, Err: _err}
+                }
+            }
+            if err == io.EOF {
+                break
+            } else if err != nil {
+                console_log("ERROR", err.Error())
+                return false, err
+            }
+        }
+    }
+    return true, nil
+}
+
+/* _record_source记录(section, key)这对组合最终由哪个文件提供, 供Source查询
+ */
+func (config *Config) _record_source(section, key, fname string) {
+    if fname == "" {
+        return
+    }
+    by_key, _in := config.provenance[section]
+    if !_in {
+        by_key = make(map[string]string)
+        config.provenance[section] = by_key
+    }
+    by_key[key] = fname
+}
+
+/* _include实现include = path指令: 相对于当前正在解析的文件所在目录解析path,
+ * 通过visited集合检测环, 通过depth防止病态的嵌套
+ */
+func (config *Config) _include(path string) (bool, error) {
+    config._pstate.include_depth++
+    defer func() { config._pstate.include_depth-- }()
+    if config._pstate.include_depth > maxIncludeDepth {
+        return false, &ErrMaxDepth{maxIncludeDepth}
+    }
+    base_dir := filepath.Dir(config._pstate.cur_file)
+    full_path := path
+    if !filepath.IsAbs(path) && config._pstate.cur_file != "" {
+        full_path = filepath.Join(base_dir, path)
+    }
+    abs_path, err := filepath.Abs(full_path)
+    if err != nil {
+        return false, err
+    }
+    if config.visited[abs_path] {
+        return false, &ErrIncludeCycle{abs_path}
+    }
+
+    file, err := os.Open(abs_path)
+    if err != nil {
+        return false, err
+    }
+    defer file.Close()
+
+    config.visited[abs_path] = true
+    defer delete(config.visited, abs_path)
+
+    prev_file := config._pstate.cur_file
+    prev_line := config._pstate.cur_line
+    config._pstate.cur_file = abs_path
+    config._pstate.cur_line = 0
+    defer func() {
+        config._pstate.cur_file = prev_file
+        config._pstate.cur_line = prev_line
+    }()
+
+    br := bufio.NewReader(file)
+    for {
+        line, _, err := br.ReadLine()
+        if err == nil || err == io.EOF {
+            config._pstate.cur_line++
+            _success, _err := config._parse_line(string(line))
+            if !_success {
+                return false, &ConfFileError{File: abs_path, Err: _err}
+            }
+        }
+        if err == io.EOF {
+            break
+        } else if err != nil {
+            return false, err
+        }
+    }
+    return true, nil
+}
+
+/* 解析完成后的善后工作
+ */
+func (config *Config) finalize() {
+    for _, fobj := range config.conf_files {
+        fobj.Close()
+    }
+}
+
+/* 解析配置
+ */
+func (config *Config) Parse_conf() (bool, error) {
+    _success, _err := config._open_files()
+    defer config.finalize()
+    if !_success {
+        return false, _err
+    }
+    for _, fname := range config.file_order {
+        _success, _err = config._parse_file(fname, config.conf_files[fname])
+        if !_success {
+            return false, _err
+        }
+    }
+    if _err := config._interpolate(); _err != nil {
+        return false, _err
+    }
+    return true, nil
+}
+
+/* Source返回指定(section, key)最终取值来自哪个文件, 未知则返回空字符串
+ */
+func (config *Config) Source(section, key string) string {
+    config.mu.RLock()
+    defer config.mu.RUnlock()
+    by_key, _in := config.provenance[section]
+    if !_in {
+        return ""
+    }
+    return by_key[key]
+}
+
+/* 获取指定节下的指定关键字的值
+ */
+func (config *Config) Get(section, key string) (string, error) {
+    config.mu.RLock()
+    defer config.mu.RUnlock()
+    pSection, _in := config.sections[section]
+    if !_in {
+        return "", &NoSuchSectionError{section}
+    }
+    value, _in := pSection.fields[key]
+    if !_in {
+        return "", &NoSuchKeyError{section, key}
+    }
+    return value, nil
+}
+
+/* iniConfig是Configer在ini格式上的适配器, 内嵌Config以复用其全部解析/访问能力
+ */
+type iniConfig struct {
+    *Config
+}
+
+func init() {
+    Register("ini", func() Configer {
+        return &iniConfig{newIniBackend(nil)}
+    })
+}
+
+func (c *iniConfig) loadFiles(filenames []string) error {
+    c.Config = newIniBackend(filenames)
+    _success, err := c.Config.Parse_conf()
+    if !_success {
+        return err
+    }
+    return nil
+}
+
+/* split_key把Configer统一的"section::key"地址拆成ini适配器内部使用的(section, key)
+ */
+func split_key(key string) (string, string, error) {
+    parts := strings.SplitN(key, "::", 2)
+    if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+        return "", "", &NoSuchKeyError{key, ""}
+    }
+    return parts[0], parts[1], nil
+}
+
+func (c *iniConfig) Get(key string) (string, error) {
+    section, subkey, err := split_key(key)
+    if err != nil {
+        return "", err
+    }
+    return c.Config.Get(section, subkey)
+}
+
+func (c *iniConfig) String(key string) string {
+    value, _ := c.Get(key)
+    return value
+}
+
+func (c *iniConfig) Int(key string) (int, error) {
+    section, subkey, err := split_key(key)
+    if err != nil {
+        return 0, err
+    }
+    return c.Config.GetInt(section, subkey)
+}
+
+func (c *iniConfig) Int64(key string) (int64, error) {
+    section, subkey, err := split_key(key)
+    if err != nil {
+        return 0, err
+    }
+    return c.Config.GetInt64(section, subkey)
+}
+
+func (c *iniConfig) Bool(key string) (bool, error) {
+    section, subkey, err := split_key(key)
+    if err != nil {
+        return false, err
+    }
+    return c.Config.GetBool(section, subkey)
+}
+
+func (c *iniConfig) Float(key string) (float64, error) {
+    section, subkey, err := split_key(key)
+    if err != nil {
+        return 0, err
+    }
+    return c.Config.GetFloat64(section, subkey)
+}
+
+func (c *iniConfig) DIY(key string) (interface{}, error) {
+    return c.Get(key)
+}
+
+func (c *iniConfig) Set(key, value string) error {
+    section, subkey, err := split_key(key)
+    if err != nil {
+        return err
+    }
+    c.Config.Set(section, subkey, value)
+    return nil
+}
+
+func (c *iniConfig) Sections() []string {
+    return c.Config.Sections()
+}
+
+func (c *iniConfig) Keys(section string) []string {
+    return c.Config.Keys(section)
+}
+
+func (c *iniConfig) SaveTo(filename string) error {
+    return c.Config.SaveTo(filename)
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+    "strconv"
+    "strings"
+    "time"
+)
+
+/* GetInt获取指定节下的指定关键字并解析为int
+ */
+func (config *Config) GetInt(section, key string) (int, error) {
+    value, err := config.Get(section, key)
+    if err != nil {
+        return 0, err
+    }
+    return strconv.Atoi(value)
+}
+
+/* GetInt64获取指定节下的指定关键字并解析为int64
+ */
+func (config *Config) GetInt64(section, key string) (int64, error) {
+    value, err := config.Get(section, key)
+    if err != nil {
+        return 0, err
+    }
+    return strconv.ParseInt(value, 10, 64)
+}
+
+/* GetFloat64获取指定节下的指定关键字并解析为float64
+ */
+func (config *Config) GetFloat64(section, key string) (float64, error) {
+    value, err := config.Get(section, key)
+    if err != nil {
+        return 0, err
+    }
+    return strconv.ParseFloat(value, 64)
+}
+
+/* GetBool获取指定节下的指定关键字并解析为bool
+ * 除了Go标准的true/false, 也接受t/y/yes/on/1表示true, f/n/no/off/0表示false(大小写不敏感),
+ * 这与大多数ini解析库的习惯一致
+ */
+func (config *Config) GetBool(section, key string) (bool, error) {
+    value, err := config.Get(section, key)
+    if err != nil {
+        return false, err
+    }
+    switch strings.ToLower(value) {
+    case "t", "true", "y", "yes", "on", "1":
+        return true, nil
+    case "f", "false", "n", "no", "off", "0":
+        return false, nil
+    }
+    return false, &ConfLineError{Reason: "cannot parse as bool", Text: value}
+}
+
+/* GetDuration获取指定节下的指定关键字并通过time.ParseDuration解析, 例如"5s", "1h30m"
+ */
+func (config *Config) GetDuration(section, key string) (time.Duration, error) {
+    value, err := config.Get(section, key)
+    if err != nil {
+        return 0, err
+    }
+    return time.ParseDuration(value)
+}
+
+/* MustGet系列在section/key不存在或解析失败时静默返回调用方提供的默认值, 适合不想处理error的场景
+ */
+func (config *Config) MustGet(section, key, dft string) string {
+    value, err := config.Get(section, key)
+    if err != nil {
+        return dft
+    }
+    return value
+}
+
+func (config *Config) MustGetInt(section, key string, dft int) int {
+    value, err := config.GetInt(section, key)
+    if err != nil {
+        return dft
+    }
+    return value
+}
+
+func (config *Config) MustGetInt64(section, key string, dft int64) int64 {
+    value, err := config.GetInt64(section, key)
+    if err != nil {
+        return dft
+    }
+    return value
+}
+
+func (config *Config) MustGetFloat64(section, key string, dft float64) float64 {
+    value, err := config.GetFloat64(section, key)
+    if err != nil {
+        return dft
+    }
+    return value
+}
+
+func (config *Config) MustGetBool(section, key string, dft bool) bool {
+    value, err := config.GetBool(section, key)
+    if err != nil {
+        return dft
+    }
+    return value
+}
+
+func (config *Config) MustGetDuration(section, key string, dft time.Duration) time.Duration {
+    value, err := config.GetDuration(section, key)
+    if err != nil {
+        return dft
+    }
+    return value
+}
+
+/* GetSection返回指定节下全部配置项的一份拷贝, 调用方对返回值的修改不会影响Config本身
+ */
+func (config *Config) GetSection(name string) (map[string]string, error) {
+    config.mu.RLock()
+    defer config.mu.RUnlock()
+    pSection, _in := config.sections[name]
+    if !_in {
+        return nil, &NoSuchSectionError{name}
+    }
+    fields := make(map[string]string, len(pSection.fields))
+    for key, value := range pSection.fields {
+        fields[key] = value
+    }
+    return fields, nil
+}
+
+/* Sections返回已解析的全部节名, 顺序与节在文件中出现的顺序一致(即section_order)
+ */
+func (config *Config) Sections() []string {
+    config.mu.RLock()
+    defer config.mu.RUnlock()
+    names := make([]string, len(config.section_order))
+    copy(names, config.section_order)
+    return names
+}
+
+/* Keys返回指定节下的全部关键字, 顺序与关键字在文件中出现的顺序一致; 节不存在时返回nil
+ */
+func (config *Config) Keys(section string) []string {
+    config.mu.RLock()
+    defer config.mu.RUnlock()
+    pSection, _in := config.sections[section]
+    if !_in {
+        return nil
+    }
+    keys := make([]string, len(pSection.keys))
+    copy(keys, pSection.keys)
+    return keys
+}
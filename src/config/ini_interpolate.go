@@ -0,0 +1,54 @@
+package config
+
+import (
+    "regexp"
+)
+
+var interpolation_pattern = regexp.MustCompile(`%\(([A-Za-z0-9_.]+)\)s`)
+
+/* _interpolate在Parse_conf完成全部文件(含include)的解析后运行一遍, 把每个值里的
+ * %(name)s替换为同一节下name对应的值, 本节找不到时回退到DEFAULT节。
+ * 一个值可能引用另一个同样含有占位符的值, 所以反复替换直到不再变化或达到maxInterpolationDepth,
+ * 后者通常意味着存在循环引用。
+ */
+func (config *Config) _interpolate() error {
+    for _, pSection := range config.sections {
+        for _, key := range pSection.keys {
+            resolved, err := config._resolve_value(pSection, pSection.fields[key], 0)
+            if err != nil {
+                return err
+            }
+            pSection.fields[key] = resolved
+        }
+    }
+    return nil
+}
+
+func (config *Config) _resolve_value(section *Section, value string, depth int) (string, error) {
+    if !interpolation_pattern.MatchString(value) {
+        return value, nil
+    }
+    if depth >= maxInterpolationDepth {
+        return "", &ErrMaxDepth{maxInterpolationDepth}
+    }
+
+    replaced := interpolation_pattern.ReplaceAllStringFunc(value, func(match string) string {
+        name := interpolation_pattern.FindStringSubmatch(match)[1]
+        if v, ok := section.fields[name]; ok {
+            return v
+        }
+        if dflt, ok := config.sections["DEFAULT"]; ok {
+            if v, ok := dflt.fields[name]; ok {
+                return v
+            }
+        }
+        // 引用的key不存在时原样保留占位符, 交由调用方在Get时发现值里还带着%(...)s
+        return match
+    })
+
+    if replaced == value {
+        // 占位符引用的key不存在, 无法再继续展开
+        return replaced, nil
+    }
+    return config._resolve_value(section, replaced, depth+1)
+}
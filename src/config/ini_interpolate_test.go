@@ -0,0 +1,79 @@
+package config
+
+import (
+    "errors"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+/* TestInterpolationResolvesChain覆盖%(name)s插值的正常链式展开以及DEFAULT节回退
+ */
+func TestInterpolationResolvesChain(t *testing.T) {
+    dir := t.TempDir()
+    src := filepath.Join(dir, "chain.ini")
+    content := "[DEFAULT]\nroot = example.com\n\n[server]\nhost = %(root)s\nurl = http://%(host)s\n"
+    if err := os.WriteFile(src, []byte(content), 0644); err != nil {
+        t.Fatal(err)
+    }
+
+    config := newIniBackend([]string{src})
+    if _success, _err := config.Parse_conf(); !_success {
+        t.Fatal(_err)
+    }
+
+    if value, err := config.Get("server", "url"); err != nil || value != "http://example.com" {
+        t.Fatalf("got (%q, %v), want (\"http://example.com\", nil)", value, err)
+    }
+}
+
+/* TestInterpolationCycleHitsMaxDepth覆盖%(name)s循环引用(a引用b, b引用a): 不应死循环,
+ * 而应在达到maxInterpolationDepth时返回ErrMaxDepth
+ */
+func TestInterpolationCycleHitsMaxDepth(t *testing.T) {
+    dir := t.TempDir()
+    src := filepath.Join(dir, "cycle.ini")
+    content := "[server]\na = %(b)s\nb = %(a)s\n"
+    if err := os.WriteFile(src, []byte(content), 0644); err != nil {
+        t.Fatal(err)
+    }
+
+    config := newIniBackend([]string{src})
+    _success, err := config.Parse_conf()
+    if _success {
+        t.Fatal("expected Parse_conf to fail on a circular interpolation reference")
+    }
+    var max_depth_err *ErrMaxDepth
+    if !errors.As(err, &max_depth_err) {
+        t.Fatalf("got error %v (%T), want *ErrMaxDepth", err, err)
+    }
+}
+
+/* TestIncludeCycleDetected覆盖include = path形成环(a include b, b include a)时应立即
+ * 失败并返回错误文本中带有include cycle的*ConfFileError, 而不是无限递归
+ */
+func TestIncludeCycleDetected(t *testing.T) {
+    dir := t.TempDir()
+    a := filepath.Join(dir, "a.ini")
+    b := filepath.Join(dir, "b.ini")
+    if err := os.WriteFile(a, []byte("[x]\nk = 1\ninclude = b.ini\n"), 0644); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.WriteFile(b, []byte("[y]\nk = 2\ninclude = a.ini\n"), 0644); err != nil {
+        t.Fatal(err)
+    }
+
+    config := newIniBackend([]string{a})
+    _success, err := config.Parse_conf()
+    if _success {
+        t.Fatal("expected Parse_conf to fail on an include cycle")
+    }
+    var file_err *ConfFileError
+    if !errors.As(err, &file_err) {
+        t.Fatalf("got error %v (%T), want *ConfFileError", err, err)
+    }
+    if !strings.Contains(err.Error(), "include cycle") {
+        t.Fatalf("got error %q, want it to mention an include cycle", err.Error())
+    }
+}
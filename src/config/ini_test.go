@@ -0,0 +1,133 @@
+package config
+
+import (
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+/* TestSaveToRoundTripEmptyValue覆盖chunk0-4引入的round-trip编辑: 一个key的值为空字符串时
+ * SaveTo写出的"key = "必须能被Parse_conf原样读回, 而不是触发_parse_line里对value[0]的越界panic
+ */
+func TestSaveToRoundTripEmptyValue(t *testing.T) {
+    dir := t.TempDir()
+    src := filepath.Join(dir, "source.ini")
+    if err := os.WriteFile(src, []byte("[server]\nhost = localhost\n"), 0644); err != nil {
+        t.Fatal(err)
+    }
+
+    config := newIniBackend([]string{src})
+    if _success, _err := config.Parse_conf(); !_success {
+        t.Fatal(_err)
+    }
+    config.Set("server", "note", "")
+
+    out := filepath.Join(dir, "out.ini")
+    if err := config.SaveTo(out); err != nil {
+        t.Fatal(err)
+    }
+
+    reloaded := newIniBackend([]string{out})
+    if _success, _err := reloaded.Parse_conf(); !_success {
+        t.Fatalf("reloading a file with an empty value should not fail: %v", _err)
+    }
+
+    if value, err := reloaded.Get("server", "note"); err != nil || value != "" {
+        t.Fatalf("got (%q, %v), want (\"\", nil)", value, err)
+    }
+    if value, err := reloaded.Get("server", "host"); err != nil || value != "localhost" {
+        t.Fatalf("got (%q, %v), want (\"localhost\", nil)", value, err)
+    }
+}
+
+/* TestSectionsAndKeysPreserveFileOrder覆盖chunk0-3: Sections/Keys必须按section_order/
+ * Section.keys返回的文件顺序来, 而不是map的随机遍历顺序
+ */
+func TestSectionsAndKeysPreserveFileOrder(t *testing.T) {
+    dir := t.TempDir()
+    src := filepath.Join(dir, "ordered.ini")
+    content := "[zeta]\nc = 1\na = 2\nb = 3\n\n[alpha]\nk = 1\n\n[mu]\nk = 1\n"
+    if err := os.WriteFile(src, []byte(content), 0644); err != nil {
+        t.Fatal(err)
+    }
+
+    config := newIniBackend([]string{src})
+    if _success, _err := config.Parse_conf(); !_success {
+        t.Fatal(_err)
+    }
+
+    want_sections := []string{"zeta", "alpha", "mu"}
+    for i := 0; i < 20; i++ {
+        got := config.Sections()
+        if len(got) != len(want_sections) {
+            t.Fatalf("got %v, want %v", got, want_sections)
+        }
+        for j, name := range want_sections {
+            if got[j] != name {
+                t.Fatalf("got %v, want %v", got, want_sections)
+            }
+        }
+    }
+
+    want_keys := []string{"c", "a", "b"}
+    for i := 0; i < 20; i++ {
+        got := config.Keys("zeta")
+        if len(got) != len(want_keys) {
+            t.Fatalf("got %v, want %v", got, want_keys)
+        }
+        for j, key := range want_keys {
+            if got[j] != key {
+                t.Fatalf("got %v, want %v", got, want_keys)
+            }
+        }
+    }
+}
+
+/* TestSaveToWriterBlankLineBeforeSectionComment覆盖chunk0-4的SaveToWriter: section之间的
+ * 空行分隔符必须写在该section自己的注释之前, 不能夹在注释和[header]之间, 否则看起来像是
+ * 这段注释属于前一个section
+ */
+func TestSaveToWriterBlankLineBeforeSectionComment(t *testing.T) {
+    dir := t.TempDir()
+    src := filepath.Join(dir, "commented.ini")
+    content := "[a]\nk = 1\n\n# about b\n[b]\nk = 2\n"
+    if err := os.WriteFile(src, []byte(content), 0644); err != nil {
+        t.Fatal(err)
+    }
+
+    config := newIniBackend([]string{src})
+    if _success, _err := config.Parse_conf(); !_success {
+        t.Fatal(_err)
+    }
+
+    var buf strings.Builder
+    if err := config.SaveToWriter(&buf); err != nil {
+        t.Fatal(err)
+    }
+
+    want := "[a]\nk = 1\n\n# about b\n[b]\nk = 2\n"
+    if got := buf.String(); got != want {
+        t.Fatalf("got:\n%q\nwant:\n%q", got, want)
+    }
+}
+
+/* TestParseLineEmptyKeyReportsItsOwnReason覆盖chunk0-6: 一行以"="开头(key为空)必须报告
+ * "key cannot be empty", 而不是被first_equation_pos<=0的检查抢先报成"invalid configuration line"
+ */
+func TestParseLineEmptyKeyReportsItsOwnReason(t *testing.T) {
+    dir := t.TempDir()
+    src := filepath.Join(dir, "bad.ini")
+    if err := os.WriteFile(src, []byte("[server]\n= value\n"), 0644); err != nil {
+        t.Fatal(err)
+    }
+
+    config := newIniBackend([]string{src})
+    _success, err := config.Parse_conf()
+    if _success {
+        t.Fatal("expected Parse_conf to fail on a line with an empty key")
+    }
+    if !strings.Contains(err.Error(), "key cannot be empty") {
+        t.Fatalf("got error %q, want it to mention \"key cannot be empty\"", err.Error())
+    }
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+    "fmt"
+    "io"
+    "os"
+    "strings"
+)
+
+/* Set写入(或覆盖)指定节下的指定关键字, 节不存在时会被自动创建
+ * 这是round-trip编辑的基础: 配合SaveTo可以把内存中的改动写回ini文件
+ */
+func (config *Config) Set(section, key, value string) {
+    config.mu.Lock()
+    defer config.mu.Unlock()
+    pSection := config._ensure_section(section)
+    if _, _in := pSection.fields[key]; !_in {
+        pSection.keys = append(pSection.keys, key)
+    }
+    pSection.fields[key] = value
+}
+
+/* _ensure_section返回section对应的*Section, 不存在则创建并追加到section_order中
+ * 调用方需自行持有config.mu
+ */
+func (config *Config) _ensure_section(section string) *Section {
+    pSection, _in := config.sections[section]
+    if !_in {
+        pSection = NewSection(section)
+        config.sections[section] = pSection
+        config.section_order = append(config.section_order, section)
+    }
+    return pSection
+}
+
+/* NewSection在Config上创建一个空节并返回它, 已存在则直接返回已有的节
+ */
+func (config *Config) NewSection(name string) *Section {
+    config.mu.Lock()
+    defer config.mu.Unlock()
+    return config._ensure_section(name)
+}
+
+/* DeleteKey删除指定节下的指定关键字, 节或关键字不存在时是no-op
+ */
+func (config *Config) DeleteKey(section, key string) {
+    config.mu.Lock()
+    defer config.mu.Unlock()
+    pSection, _in := config.sections[section]
+    if !_in {
+        return
+    }
+    if _, _in := pSection.fields[key]; !_in {
+        return
+    }
+    delete(pSection.fields, key)
+    delete(pSection.key_comments, key)
+    for i, k := range pSection.keys {
+        if k == key {
+            pSection.keys = append(pSection.keys[:i], pSection.keys[i+1:]...)
+            break
+        }
+    }
+}
+
+/* DeleteSection删除整个节, 不存在时是no-op
+ */
+func (config *Config) DeleteSection(section string) {
+    config.mu.Lock()
+    defer config.mu.Unlock()
+    if _, _in := config.sections[section]; !_in {
+        return
+    }
+    delete(config.sections, section)
+    for i, name := range config.section_order {
+        if name == section {
+            config.section_order = append(config.section_order[:i], config.section_order[i+1:]...)
+            break
+        }
+    }
+}
+
+/* SaveTo把当前配置序列化为ini格式并写入filename, 保留节/关键字的插入顺序及注释
+ */
+func (config *Config) SaveTo(filename string) error {
+    f, err := os.Create(filename)
+    if err != nil {
+        return err
+    }
+    defer f.Close()
+    return config.SaveToWriter(f)
+}
+
+/* SaveToWriter与SaveTo相同, 但写到调用方提供的io.Writer, 便于测试或写到非文件目标
+ */
+func (config *Config) SaveToWriter(w io.Writer) error {
+    config.mu.RLock()
+    defer config.mu.RUnlock()
+
+    for i, sec_name := range config.section_order {
+        pSection := config.sections[sec_name]
+        if i > 0 {
+            if _, err := fmt.Fprintln(w); err != nil {
+                return err
+            }
+        }
+        if pSection.comment != "" {
+            if err := _write_comment(w, pSection.comment); err != nil {
+                return err
+            }
+        }
+        if _, err := fmt.Fprintf(w, "[%s]\n", sec_name); err != nil {
+            return err
+        }
+        for _, key := range pSection.keys {
+            if comment, _in := pSection.key_comments[key]; _in {
+                if err := _write_comment(w, comment); err != nil {
+                    return err
+                }
+            }
+            if _, err := fmt.Fprintf(w, "%s = %s\n", key, pSection.fields[key]); err != nil {
+                return err
+            }
+        }
+    }
+    return nil
+}
+
+func _write_comment(w io.Writer, comment string) error {
+    for _, line := range strings.Split(comment, "\n") {
+        if _, err := fmt.Fprintf(w, "# %s\n", line); err != nil {
+            return err
+        }
+    }
+    return nil
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "strconv"
+    "strings"
+)
+
+/* jsonConfig是Configer在JSON格式上的适配器
+ * data: 解析后的JSON对象, 顶层key是节名, 次层key是节内的配置项
+ * flat: data按"section::subkey"展开后的字符串视图, 供Get/String等使用
+ */
+type jsonConfig struct {
+    data map[string]interface{}
+    flat map[string]string
+}
+
+func init() {
+    Register("json", func() Configer {
+        return &jsonConfig{data: make(map[string]interface{}), flat: make(map[string]string)}
+    })
+}
+
+func (c *jsonConfig) loadFiles(filenames []string) error {
+    if len(filenames) != 1 {
+        return &ConfFileError{File: strings.Join(filenames, ","), Err: &ConfLineError{Reason: "json adapter expects exactly one file"}}
+    }
+    raw, err := os.ReadFile(filenames[0])
+    if err != nil {
+        return err
+    }
+    return c.loadData(raw)
+}
+
+func (c *jsonConfig) loadData(data []byte) error {
+    var top map[string]interface{}
+    if err := json.Unmarshal(data, &top); err != nil {
+        return err
+    }
+    c.data = top
+    c.flat = make(map[string]string)
+    flatten_into("", top, c.flat)
+    return nil
+}
+
+/* flatten_into把嵌套的map/对象展开成"a::b::c"形式的扁平字符串视图
+ */
+func flatten_into(prefix string, value interface{}, out map[string]string) {
+    switch v := value.(type) {
+    case map[string]interface{}:
+        for key, sub := range v {
+            child := key
+            if prefix != "" {
+                child = prefix + "::" + key
+            }
+            flatten_into(child, sub, out)
+        }
+    case string:
+        out[prefix] = v
+    case bool:
+        out[prefix] = strconv.FormatBool(v)
+    case float64:
+        out[prefix] = strconv.FormatFloat(v, 'f', -1, 64)
+    case nil:
+        out[prefix] = ""
+    default:
+        out[prefix] = fmt.Sprint(v)
+    }
+}
+
+func (c *jsonConfig) Get(key string) (string, error) {
+    value, _in := c.flat[key]
+    if !_in {
+        return "", &NoSuchKeyError{"", key}
+    }
+    return value, nil
+}
+
+func (c *jsonConfig) String(key string) string {
+    value, _ := c.Get(key)
+    return value
+}
+
+func (c *jsonConfig) Int(key string) (int, error) {
+    value, err := c.Get(key)
+    if err != nil {
+        return 0, err
+    }
+    return strconv.Atoi(value)
+}
+
+func (c *jsonConfig) Int64(key string) (int64, error) {
+    value, err := c.Get(key)
+    if err != nil {
+        return 0, err
+    }
+    return strconv.ParseInt(value, 10, 64)
+}
+
+func (c *jsonConfig) Bool(key string) (bool, error) {
+    value, err := c.Get(key)
+    if err != nil {
+        return false, err
+    }
+    return strconv.ParseBool(value)
+}
+
+func (c *jsonConfig) Float(key string) (float64, error) {
+    value, err := c.Get(key)
+    if err != nil {
+        return 0, err
+    }
+    return strconv.ParseFloat(value, 64)
+}
+
+func (c *jsonConfig) DIY(key string) (interface{}, error) {
+    parts := strings.Split(key, "::")
+    var cur interface{} = c.data
+    for _, part := range parts {
+        m, ok := cur.(map[string]interface{})
+        if !ok {
+            return nil, &NoSuchKeyError{"", key}
+        }
+        cur, ok = m[part]
+        if !ok {
+            return nil, &NoSuchKeyError{"", key}
+        }
+    }
+    return cur, nil
+}
+
+func (c *jsonConfig) Set(key, value string) error {
+    c.flat[key] = value
+
+    parts := strings.Split(key, "::")
+    m := c.data
+    for _, part := range parts[:len(parts)-1] {
+        sub, ok := m[part].(map[string]interface{})
+        if !ok {
+            sub = make(map[string]interface{})
+            m[part] = sub
+        }
+        m = sub
+    }
+    m[parts[len(parts)-1]] = value
+    return nil
+}
+
+func (c *jsonConfig) Sections() []string {
+    names := make([]string, 0, len(c.data))
+    for name := range c.data {
+        names = append(names, name)
+    }
+    return names
+}
+
+func (c *jsonConfig) Keys(section string) []string {
+    sub, ok := c.data[section].(map[string]interface{})
+    if !ok {
+        return nil
+    }
+    keys := make([]string, 0, len(sub))
+    for key := range sub {
+        keys = append(keys, key)
+    }
+    return keys
+}
+
+func (c *jsonConfig) SaveTo(filename string) error {
+    raw, err := json.MarshalIndent(c.data, "", "  ")
+    if err != nil {
+        return err
+    }
+    return os.WriteFile(filename, raw, 0644)
+}
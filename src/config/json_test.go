@@ -0,0 +1,39 @@
+package config
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+/* TestJSONConfigSetUpdatesSaveTo覆盖chunk0-2的jsonConfig: Set必须同时更新data, 否则SaveTo
+ * (它序列化data而不是flat)会悄悄丢掉Set写入的修改
+ */
+func TestJSONConfigSetUpdatesSaveTo(t *testing.T) {
+    dir := t.TempDir()
+    src := filepath.Join(dir, "source.json")
+    if err := os.WriteFile(src, []byte(`{"db": {"host": "orig"}}`), 0644); err != nil {
+        t.Fatal(err)
+    }
+
+    configer, err := NewConfig("json", src)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if err := configer.Set("db::host", "CHANGED"); err != nil {
+        t.Fatal(err)
+    }
+
+    out := filepath.Join(dir, "out.json")
+    if err := configer.SaveTo(out); err != nil {
+        t.Fatal(err)
+    }
+
+    reloaded, err := NewConfig("json", out)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if value, err := reloaded.Get("db::host"); err != nil || value != "CHANGED" {
+        t.Fatalf("got (%q, %v), want (\"CHANGED\", nil)", value, err)
+    }
+}
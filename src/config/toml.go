@@ -0,0 +1,205 @@
+package config
+
+import (
+    "bufio"
+    "bytes"
+    "os"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+/* tomlConfig是Configer在一个TOML子集上的适配器: 支持[section]、key = value(字符串/数字/布尔),
+ * 不支持数组、内联表、多级表等高级特性
+ */
+type tomlConfig struct {
+    sections map[string]map[string]string
+}
+
+func init() {
+    Register("toml", func() Configer {
+        return &tomlConfig{sections: make(map[string]map[string]string)}
+    })
+}
+
+func (c *tomlConfig) loadFiles(filenames []string) error {
+    if len(filenames) != 1 {
+        return &ConfFileError{File: strings.Join(filenames, ","), Err: &ConfLineError{Reason: "toml adapter expects exactly one file"}}
+    }
+    raw, err := os.ReadFile(filenames[0])
+    if err != nil {
+        return err
+    }
+    return c.loadData(raw)
+}
+
+func (c *tomlConfig) loadData(data []byte) error {
+    c.sections = make(map[string]map[string]string)
+    cur := ""
+    c.sections[cur] = make(map[string]string)
+
+    scanner := bufio.NewScanner(bytes.NewReader(data))
+    for scanner.Scan() {
+        line := strings.TrimSpace(scanner.Text())
+        if line == "" || strings.HasPrefix(line, "#") {
+            continue
+        }
+        if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+            cur = strings.TrimSpace(line[1 : len(line)-1])
+            if _, ok := c.sections[cur]; !ok {
+                c.sections[cur] = make(map[string]string)
+            }
+            continue
+        }
+        pos := strings.Index(line, "=")
+        if pos <= 0 {
+            return &ConfLineError{Reason: "invalid toml line", Text: line}
+        }
+        key := strings.TrimSpace(line[:pos])
+        value := strings.TrimSpace(line[pos+1:])
+        if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+            value = value[1 : len(value)-1]
+        }
+        c.sections[cur][key] = value
+    }
+    return scanner.Err()
+}
+
+func (c *tomlConfig) Get(key string) (string, error) {
+    section, subkey, err := split_key(key)
+    if err != nil {
+        return "", err
+    }
+    fields, ok := c.sections[section]
+    if !ok {
+        return "", &NoSuchSectionError{section}
+    }
+    value, ok := fields[subkey]
+    if !ok {
+        return "", &NoSuchKeyError{section, subkey}
+    }
+    return value, nil
+}
+
+func (c *tomlConfig) String(key string) string {
+    value, _ := c.Get(key)
+    return value
+}
+
+func (c *tomlConfig) Int(key string) (int, error) {
+    value, err := c.Get(key)
+    if err != nil {
+        return 0, err
+    }
+    return strconv.Atoi(value)
+}
+
+func (c *tomlConfig) Int64(key string) (int64, error) {
+    value, err := c.Get(key)
+    if err != nil {
+        return 0, err
+    }
+    return strconv.ParseInt(value, 10, 64)
+}
+
+func (c *tomlConfig) Bool(key string) (bool, error) {
+    value, err := c.Get(key)
+    if err != nil {
+        return false, err
+    }
+    return strconv.ParseBool(value)
+}
+
+func (c *tomlConfig) Float(key string) (float64, error) {
+    value, err := c.Get(key)
+    if err != nil {
+        return 0, err
+    }
+    return strconv.ParseFloat(value, 64)
+}
+
+func (c *tomlConfig) DIY(key string) (interface{}, error) {
+    return c.Get(key)
+}
+
+func (c *tomlConfig) Set(key, value string) error {
+    section, subkey, err := split_key(key)
+    if err != nil {
+        return err
+    }
+    fields, ok := c.sections[section]
+    if !ok {
+        fields = make(map[string]string)
+        c.sections[section] = fields
+    }
+    fields[subkey] = value
+    return nil
+}
+
+func (c *tomlConfig) Sections() []string {
+    names := make([]string, 0, len(c.sections))
+    for name := range c.sections {
+        names = append(names, name)
+    }
+    return names
+}
+
+func (c *tomlConfig) Keys(section string) []string {
+    fields, ok := c.sections[section]
+    if !ok {
+        return nil
+    }
+    keys := make([]string, 0, len(fields))
+    for key := range fields {
+        keys = append(keys, key)
+    }
+    return keys
+}
+
+func (c *tomlConfig) SaveTo(filename string) error {
+    f, err := os.Create(filename)
+    if err != nil {
+        return err
+    }
+    defer f.Close()
+
+    // unsectioned keys must come first: writing them after a [section] header
+    // would silently reassign them to that section on reload
+    if fields, ok := c.sections[""]; ok {
+        if err := write_toml_fields(f, fields); err != nil {
+            return err
+        }
+    }
+    names := make([]string, 0, len(c.sections))
+    for name := range c.sections {
+        if name != "" {
+            names = append(names, name)
+        }
+    }
+    sort.Strings(names)
+    for _, name := range names {
+        if _, err := f.WriteString("[" + name + "]\n"); err != nil {
+            return err
+        }
+        if err := write_toml_fields(f, c.sections[name]); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+/* write_toml_fields按key的字典序写出一个section的全部键值对, 使SaveTo的输出在多次调用间保持稳定
+ */
+func write_toml_fields(f *os.File, fields map[string]string) error {
+    keys := make([]string, 0, len(fields))
+    for key := range fields {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+    for _, key := range keys {
+        if _, err := f.WriteString(key + " = \"" + fields[key] + "\"\n"); err != nil {
+            return err
+        }
+    }
+    return nil
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+/* TestTomlSaveToOrdersUnsectionedKeysFirst覆盖chunk0-2的tomlConfig.SaveTo: 未归属到任何
+ * section的顶层key必须写在第一个[section]之前, 否则重新加载时会被误判为归属于前一个section
+ */
+func TestTomlSaveToOrdersUnsectionedKeysFirst(t *testing.T) {
+    dir := t.TempDir()
+    src := filepath.Join(dir, "source.toml")
+    if err := os.WriteFile(src, []byte("top = \"root\"\n\n[db]\nhost = \"localhost\"\n"), 0644); err != nil {
+        t.Fatal(err)
+    }
+
+    configer, err := NewConfig("toml", src)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    out := filepath.Join(dir, "out.toml")
+    if err := configer.SaveTo(out); err != nil {
+        t.Fatal(err)
+    }
+
+    reloaded, err := NewConfig("toml", out)
+    if err != nil {
+        t.Fatal(err)
+    }
+    tc, ok := reloaded.(*tomlConfig)
+    if !ok {
+        t.Fatalf("expected *tomlConfig, got %T", reloaded)
+    }
+    if value, ok := tc.sections[""]["top"]; !ok || value != "root" {
+        t.Fatalf("got (%q, %v), want (\"root\", true)", value, ok)
+    }
+    if value, err := reloaded.Get("db::host"); err != nil || value != "localhost" {
+        t.Fatalf("got (%q, %v), want (\"localhost\", nil)", value, err)
+    }
+}
+
+/* TestTomlSaveToIsDeterministic覆盖chunk0-2: 多个section/key时, 反复SaveTo同一份配置必须
+ * 产出完全相同的字节, 否则每次写出都会因map遍历顺序随机而产生无意义的diff
+ */
+func TestTomlSaveToIsDeterministic(t *testing.T) {
+    dir := t.TempDir()
+    src := filepath.Join(dir, "source.toml")
+    content := "zeta = \"1\"\nalpha = \"2\"\n\n[db]\nhost = \"localhost\"\nport = \"5432\"\nuser = \"root\"\n\n[cache]\nttl = \"60\"\n"
+    if err := os.WriteFile(src, []byte(content), 0644); err != nil {
+        t.Fatal(err)
+    }
+
+    configer, err := NewConfig("toml", src)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    var first []byte
+    for i := 0; i < 10; i++ {
+        out := filepath.Join(dir, "out.toml")
+        if err := configer.SaveTo(out); err != nil {
+            t.Fatal(err)
+        }
+        got, err := os.ReadFile(out)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if first == nil {
+            first = got
+            continue
+        }
+        if string(got) != string(first) {
+            t.Fatalf("SaveTo produced different output across runs:\n--- run 0 ---\n%s\n--- run %d ---\n%s", first, i, got)
+        }
+    }
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+    "fmt"
+    "os"
+    "sync"
+    "time"
+)
+
+/* Listener在配置文件被重新加载后收到通知
+ * fname: 触发本次重载的文件路径
+ */
+type Listener interface {
+    Listen(fname string)
+}
+
+/* ListenerFunc让一个裸函数也能当作Listener使用
+ */
+type ListenerFunc func(fname string)
+
+func (f ListenerFunc) Listen(fname string) {
+    f(fname)
+}
+
+/* WatchMode选择文件变更的检测方式
+ */
+type WatchMode int
+
+const (
+    // ModePoll定时stat每个配置文件, 不依赖任何第三方库
+    ModePoll WatchMode = iota
+    // ModeFsnotify依赖inotify/kqueue, 本仓库未引入相应依赖, 暂不支持
+    ModeFsnotify
+)
+
+/* WatchOptions控制WatchWithOptions的行为
+ * Debounce: 同一文件短时间内多次写入只触发一次reload, 默认300ms
+ * Interval: ModePoll下的轮询周期, 默认1s
+ * Mode: 检测方式, 默认ModePoll
+ */
+type WatchOptions struct {
+    Debounce time.Duration
+    Interval time.Duration
+    Mode WatchMode
+}
+
+func defaultWatchOptions() WatchOptions {
+    return WatchOptions{
+        Debounce: 300 * time.Millisecond,
+        Interval: time.Second,
+        Mode: ModePoll,
+    }
+}
+
+/* fstat记录上一次观察到的文件状态, 用于判断是否需要重新加载
+ */
+type fstat struct {
+    mod_time time.Time
+    size int64
+}
+
+/* watcher持有热重载所需的后台goroutine及其生命周期
+ */
+type watcher struct {
+    opts WatchOptions
+    listener Listener
+    errs chan error
+    stop chan struct{}
+    done chan struct{}
+    stop_once sync.Once
+}
+
+/* Watch打开filename, 解析一次, 然后以默认选项(轮询, 300ms去抖)监控其变化,
+ * 每次重载成功后都会调用listener.Listen(filename)。
+ */
+func Watch(filename string, listener Listener) (*Config, error) {
+    return WatchWithOptions(filename, listener, defaultWatchOptions())
+}
+
+/* WatchWithOptions与Watch相同, 但允许调用方自定义去抖时间、轮询周期及检测方式
+ */
+func WatchWithOptions(filename string, listener Listener, opts WatchOptions) (*Config, error) {
+    if opts.Mode == ModeFsnotify {
+        return nil, fmt.Errorf("fsnotify watch mode is not available in this build, use ModePoll")
+    }
+    if opts.Debounce <= 0 {
+        opts.Debounce = defaultWatchOptions().Debounce
+    }
+    if opts.Interval <= 0 {
+        opts.Interval = defaultWatchOptions().Interval
+    }
+
+    config := newIniBackend([]string{filename})
+    if _success, _err := config.Parse_conf(); !_success {
+        return nil, _err
+    }
+
+    w := &watcher{
+        opts: opts,
+        listener: listener,
+        errs: make(chan error, 8),
+        stop: make(chan struct{}),
+        done: make(chan struct{}),
+    }
+    config.watcher = w
+
+    go config._watch_loop(filename, w)
+
+    return config, nil
+}
+
+/* _watch_loop定时stat filename, 一旦发现mtime/size变化(经过去抖)就重新解析
+ * 并把新的sections原子地换入正在使用的Config
+ */
+func (config *Config) _watch_loop(filename string, w *watcher) {
+    defer close(w.done)
+
+    var last fstat
+    if fi, err := os.Stat(filename); err == nil {
+        last = fstat{fi.ModTime(), fi.Size()}
+    }
+
+    ticker := time.NewTicker(w.opts.Interval)
+    defer ticker.Stop()
+
+    var pending_since time.Time
+
+    for {
+        select {
+        case <-w.stop:
+            return
+        case <-ticker.C:
+            fi, err := os.Stat(filename)
+            if err != nil {
+                w._report(err)
+                continue
+            }
+            cur := fstat{fi.ModTime(), fi.Size()}
+            if cur == last {
+                pending_since = time.Time{}
+                continue
+            }
+            if pending_since.IsZero() {
+                pending_since = time.Now()
+                continue
+            }
+            if time.Since(pending_since) < w.opts.Debounce {
+                continue
+            }
+            last = cur
+            pending_since = time.Time{}
+
+            _fresh := newIniBackend([]string{filename})
+            if _success, _err := _fresh.Parse_conf(); !_success {
+                w._report(_err)
+                continue
+            }
+
+            config.mu.Lock()
+            config.sections = _fresh.sections
+            config.section_order = _fresh.section_order
+            config.provenance = _fresh.provenance
+            config.mu.Unlock()
+
+            if w.listener != nil {
+                w.listener.Listen(filename)
+            }
+        }
+    }
+}
+
+func (w *watcher) _report(err error) {
+    select {
+    case w.errs <- err:
+    default:
+        // errs通道已满, 丢弃最旧的一条以避免阻塞reload循环
+        <-w.errs
+        w.errs <- err
+    }
+}
+
+/* Errors返回reload过程中产生的非致命错误, 调用方可选择性消费
+ */
+func (config *Config) Errors() <-chan error {
+    if config.watcher == nil {
+        return nil
+    }
+    return config.watcher.errs
+}
+
+/* Stop结束Watch/WatchWithOptions启动的后台监控goroutine, 对非watch模式的Config是no-op。
+ * 重复调用是安全的(第二次及以后为no-op), 便于调用方同时用defer Stop()和错误路径上的显式Stop()
+ */
+func (config *Config) Stop() {
+    if config.watcher == nil {
+        return
+    }
+    config.watcher.stop_once.Do(func() {
+        close(config.watcher.stop)
+    })
+    <-config.watcher.done
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+)
+
+/* TestWatchReloadSwapsOrderAndProvenance覆盖chunk0-1的_watch_loop: 重载后section_order和
+ * provenance必须和sections一起换成新值, 否则一个section被删掉之后, Sections()/Source()还会
+ * 报告旧数据, SaveToWriter更会因section_order里残留的名字在sections里查不到而panic
+ */
+func TestWatchReloadSwapsOrderAndProvenance(t *testing.T) {
+    dir := t.TempDir()
+    path := filepath.Join(dir, "watched.ini")
+    if err := os.WriteFile(path, []byte("[a]\nk = 1\n\n[b]\nk = 2\n"), 0644); err != nil {
+        t.Fatal(err)
+    }
+
+    config, err := WatchWithOptions(path, nil, WatchOptions{
+        Debounce: 10 * time.Millisecond,
+        Interval: 20 * time.Millisecond,
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer config.Stop()
+
+    // give _watch_loop a chance to capture its initial fstat before we rewrite the file
+    time.Sleep(30 * time.Millisecond)
+
+    if err := os.WriteFile(path, []byte("[a]\nk = 1\n"), 0644); err != nil {
+        t.Fatal(err)
+    }
+
+    deadline := time.Now().Add(2 * time.Second)
+    for {
+        config.mu.RLock()
+        _, has_b := config.sections["b"]
+        config.mu.RUnlock()
+        if !has_b {
+            break
+        }
+        if time.Now().After(deadline) {
+            t.Fatal("timed out waiting for reload to drop section b")
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+
+    sections := config.Sections()
+    for _, name := range sections {
+        if name == "b" {
+            t.Fatalf("section_order still references removed section %q", name)
+        }
+    }
+
+    var buf writeCounter
+    if err := config.SaveToWriter(&buf); err != nil {
+        t.Fatalf("SaveToWriter panicked/failed after reload: %v", err)
+    }
+}
+
+/* writeCounter是一个满足io.Writer的最小sink, 测试只关心SaveToWriter是否panic/出错
+ */
+type writeCounter struct {
+    n int
+}
+
+func (w *writeCounter) Write(p []byte) (int, error) {
+    w.n += len(p)
+    return len(p), nil
+}
+
+/* TestStopIsIdempotent覆盖chunk0-1的Config.Stop: 调用方常见的写法是defer config.Stop()再加
+ * 错误路径上的一次显式Stop(), 第二次调用不应该在已关闭的channel上panic
+ */
+func TestStopIsIdempotent(t *testing.T) {
+    dir := t.TempDir()
+    path := filepath.Join(dir, "watched.ini")
+    if err := os.WriteFile(path, []byte("[a]\nk = 1\n"), 0644); err != nil {
+        t.Fatal(err)
+    }
+
+    config, err := Watch(path, nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    config.Stop()
+    config.Stop()
+}
@@ -0,0 +1,221 @@
+package config
+
+import (
+    "bufio"
+    "bytes"
+    "os"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+/* yamlConfig是Configer在一个YAML子集上的适配器: 支持最多两层缩进的"key: value"映射
+ * (顶层key视为section, 第二层key视为subkey), 不支持列表、锚点、多文档等特性
+ */
+type yamlConfig struct {
+    sections map[string]map[string]string
+    top map[string]string
+}
+
+func init() {
+    Register("yaml", func() Configer {
+        return &yamlConfig{sections: make(map[string]map[string]string), top: make(map[string]string)}
+    })
+}
+
+func (c *yamlConfig) loadFiles(filenames []string) error {
+    if len(filenames) != 1 {
+        return &ConfFileError{File: strings.Join(filenames, ","), Err: &ConfLineError{Reason: "yaml adapter expects exactly one file"}}
+    }
+    raw, err := os.ReadFile(filenames[0])
+    if err != nil {
+        return err
+    }
+    return c.loadData(raw)
+}
+
+func (c *yamlConfig) loadData(data []byte) error {
+    c.sections = make(map[string]map[string]string)
+    c.top = make(map[string]string)
+
+    var cur_section string
+    in_section := false
+
+    scanner := bufio.NewScanner(bytes.NewReader(data))
+    for scanner.Scan() {
+        raw_line := scanner.Text()
+        trimmed := strings.TrimSpace(raw_line)
+        if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+            continue
+        }
+        indented := strings.HasPrefix(raw_line, " ") || strings.HasPrefix(raw_line, "\t")
+
+        pos := strings.Index(trimmed, ":")
+        if pos < 0 {
+            return &ConfLineError{Reason: "invalid yaml line", Text: trimmed}
+        }
+        key := strings.TrimSpace(trimmed[:pos])
+        value := strings.TrimSpace(trimmed[pos+1:])
+        if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+            value = value[1 : len(value)-1]
+        }
+
+        if !indented {
+            if value == "" {
+                cur_section = key
+                in_section = true
+                if _, ok := c.sections[cur_section]; !ok {
+                    c.sections[cur_section] = make(map[string]string)
+                }
+            } else {
+                in_section = false
+                c.top[key] = value
+            }
+            continue
+        }
+
+        if !in_section {
+            return &ConfLineError{Reason: "yaml line indented without a parent section", Text: trimmed}
+        }
+        c.sections[cur_section][key] = value
+    }
+    return scanner.Err()
+}
+
+func (c *yamlConfig) Get(key string) (string, error) {
+    section, subkey, err := split_key(key)
+    if err != nil {
+        value, ok := c.top[key]
+        if !ok {
+            return "", &NoSuchKeyError{"", key}
+        }
+        return value, nil
+    }
+    fields, ok := c.sections[section]
+    if !ok {
+        return "", &NoSuchSectionError{section}
+    }
+    value, ok := fields[subkey]
+    if !ok {
+        return "", &NoSuchKeyError{section, subkey}
+    }
+    return value, nil
+}
+
+func (c *yamlConfig) String(key string) string {
+    value, _ := c.Get(key)
+    return value
+}
+
+func (c *yamlConfig) Int(key string) (int, error) {
+    value, err := c.Get(key)
+    if err != nil {
+        return 0, err
+    }
+    return strconv.Atoi(value)
+}
+
+func (c *yamlConfig) Int64(key string) (int64, error) {
+    value, err := c.Get(key)
+    if err != nil {
+        return 0, err
+    }
+    return strconv.ParseInt(value, 10, 64)
+}
+
+func (c *yamlConfig) Bool(key string) (bool, error) {
+    value, err := c.Get(key)
+    if err != nil {
+        return false, err
+    }
+    return strconv.ParseBool(value)
+}
+
+func (c *yamlConfig) Float(key string) (float64, error) {
+    value, err := c.Get(key)
+    if err != nil {
+        return 0, err
+    }
+    return strconv.ParseFloat(value, 64)
+}
+
+func (c *yamlConfig) DIY(key string) (interface{}, error) {
+    return c.Get(key)
+}
+
+func (c *yamlConfig) Set(key, value string) error {
+    section, subkey, err := split_key(key)
+    if err != nil {
+        c.top[key] = value
+        return nil
+    }
+    fields, ok := c.sections[section]
+    if !ok {
+        fields = make(map[string]string)
+        c.sections[section] = fields
+    }
+    fields[subkey] = value
+    return nil
+}
+
+func (c *yamlConfig) Sections() []string {
+    names := make([]string, 0, len(c.sections))
+    for name := range c.sections {
+        names = append(names, name)
+    }
+    return names
+}
+
+func (c *yamlConfig) Keys(section string) []string {
+    fields, ok := c.sections[section]
+    if !ok {
+        return nil
+    }
+    keys := make([]string, 0, len(fields))
+    for key := range fields {
+        keys = append(keys, key)
+    }
+    return keys
+}
+
+func (c *yamlConfig) SaveTo(filename string) error {
+    f, err := os.Create(filename)
+    if err != nil {
+        return err
+    }
+    defer f.Close()
+
+    top_keys := make([]string, 0, len(c.top))
+    for key := range c.top {
+        top_keys = append(top_keys, key)
+    }
+    sort.Strings(top_keys)
+    for _, key := range top_keys {
+        if _, err := f.WriteString(key + ": " + c.top[key] + "\n"); err != nil {
+            return err
+        }
+    }
+
+    names := make([]string, 0, len(c.sections))
+    for name := range c.sections {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    for _, name := range names {
+        if _, err := f.WriteString(name + ":\n"); err != nil {
+            return err
+        }
+        fields := c.sections[name]
+        keys := make([]string, 0, len(fields))
+        for key := range fields {
+            keys = append(keys, key)
+        }
+        sort.Strings(keys)
+        for _, key := range keys {
+            if _, err := f.WriteString("  " + key + ": " + fields[key] + "\n"); err != nil {
+                return err
+            }
+        }
+    }
+    return nil
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+/* TestYamlSaveToIsDeterministic覆盖chunk0-2的yamlConfig.SaveTo: 多个section/key时, 反复
+ * SaveTo同一份配置必须产出完全相同的字节, 否则每次写出都会因map遍历顺序随机而产生无意义的diff
+ */
+func TestYamlSaveToIsDeterministic(t *testing.T) {
+    dir := t.TempDir()
+    src := filepath.Join(dir, "source.yaml")
+    content := "zeta: \"1\"\nalpha: \"2\"\ndb:\n  host: \"localhost\"\n  port: \"5432\"\n  user: \"root\"\ncache:\n  ttl: \"60\"\n"
+    if err := os.WriteFile(src, []byte(content), 0644); err != nil {
+        t.Fatal(err)
+    }
+
+    configer, err := NewConfig("yaml", src)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    var first []byte
+    for i := 0; i < 10; i++ {
+        out := filepath.Join(dir, "out.yaml")
+        if err := configer.SaveTo(out); err != nil {
+            t.Fatal(err)
+        }
+        got, err := os.ReadFile(out)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if first == nil {
+            first = got
+            continue
+        }
+        if string(got) != string(first) {
+            t.Fatalf("SaveTo produced different output across runs:\n--- run 0 ---\n%s\n--- run %d ---\n%s", first, i, got)
+        }
+    }
+}